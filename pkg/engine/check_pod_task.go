@@ -19,6 +19,8 @@ package engine
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -30,7 +32,6 @@ import (
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/NVIDIA/knavigator/pkg/config"
-	"github.com/NVIDIA/knavigator/pkg/utils"
 )
 
 // CheckPodTask represents CheckPod task.
@@ -41,19 +42,44 @@ type CheckPodTask struct {
 	BaseTask
 	checkPodTaskParams
 
-	client *kubernetes.Clientset
-	getter ObjGetter
+	client    *kubernetes.Clientset
+	getter    ObjGetter
+	informers InformerProvider
+
+	expectEvents []eventAssertion
+	forbidEvents []eventAssertion
 }
 
 type checkPodTaskParams struct {
-	RefTaskID  string            `yaml:"refTaskId"`
-	Status     string            `yaml:"status"`
-	NodeLabels map[string]string `yaml:"nodeLabels"`
-	Timeout    time.Duration     `yaml:"timeout"`
+	RefTaskID    string                 `yaml:"refTaskId"`
+	Status       string                 `yaml:"status"`
+	NodeLabels   map[string]string      `yaml:"nodeLabels"`
+	Timeout      time.Duration          `yaml:"timeout"`
+	InformerMode string                 `yaml:"informerMode"`
+	ExpectEvents []eventAssertionParams `yaml:"expectEvents"`
+	ForbidEvents []eventAssertionParams `yaml:"forbidEvents"`
+}
+
+// eventAssertionParams describes a pod Event to watch for: reason/type are matched
+// exactly when set, messageRegex is matched as a regular expression search against
+// Event.Message, and minCount is the number of matching events required (expectEvents
+// only; forbidEvents fails on the first match regardless of minCount).
+type eventAssertionParams struct {
+	Reason       string `yaml:"reason"`
+	Type         string `yaml:"type"`
+	MessageRegex string `yaml:"messageRegex"`
+	MinCount     int    `yaml:"minCount"`
+}
+
+// eventAssertion is an eventAssertionParams with its messageRegex pre-compiled once at
+// validation time rather than on every Event received.
+type eventAssertion struct {
+	eventAssertionParams
+	messageRegex *regexp.Regexp
 }
 
 // newCheckPodTask initializes and returns CheckPodTask
-func newCheckPodTask(log logr.Logger, client *kubernetes.Clientset, getter ObjGetter, cfg *config.Task) (*CheckPodTask, error) {
+func newCheckPodTask(log logr.Logger, client *kubernetes.Clientset, getter ObjGetter, informers InformerProvider, cfg *config.Task) (*CheckPodTask, error) {
 	if client == nil {
 		return nil, fmt.Errorf("%s/%s: Kubernetes client is not set", cfg.Type, cfg.ID)
 	}
@@ -64,8 +90,9 @@ func newCheckPodTask(log logr.Logger, client *kubernetes.Clientset, getter ObjGe
 			taskType: cfg.Type,
 			taskID:   cfg.ID,
 		},
-		client: client,
-		getter: getter,
+		client:    client,
+		getter:    getter,
+		informers: informers,
 	}
 
 	if err := task.validate(cfg.Params); err != nil {
@@ -93,9 +120,63 @@ func (task *CheckPodTask) validate(params map[string]interface{}) error {
 		return fmt.Errorf("%s: missing parameters 'status' and/or 'nodeLabels'", task.ID())
 	}
 
+	switch task.InformerMode {
+	case "":
+		task.InformerMode = InformerModeShared
+	case InformerModeShared, InformerModeDedicated:
+	default:
+		return fmt.Errorf("%s: invalid 'informerMode' %q, expected %q or %q", task.ID(), task.InformerMode, InformerModeShared, InformerModeDedicated)
+	}
+
+	if (len(task.ExpectEvents) > 0 || len(task.ForbidEvents) > 0) && task.Timeout == 0 {
+		return fmt.Errorf("%s: 'expectEvents'/'forbidEvents' require a non-zero 'timeout'", task.ID())
+	}
+
+	if task.expectEvents, err =compileEventAssertions(task.ExpectEvents, 1); err != nil {
+		return fmt.Errorf("%s: invalid 'expectEvents': %v", task.ID(), err)
+	}
+	if task.forbidEvents, err = compileEventAssertions(task.ForbidEvents, 0); err != nil {
+		return fmt.Errorf("%s: invalid 'forbidEvents': %v", task.ID(), err)
+	}
+
 	return nil
 }
 
+// compileEventAssertions pre-compiles each assertion's messageRegex and applies
+// defaultMinCount to assertions that don't set minCount.
+func compileEventAssertions(params []eventAssertionParams, defaultMinCount int) ([]eventAssertion, error) {
+	assertions := make([]eventAssertion, len(params))
+	for i, p := range params {
+		if p.MinCount == 0 {
+			p.MinCount = defaultMinCount
+		}
+		assertions[i] = eventAssertion{eventAssertionParams: p}
+		if len(p.MessageRegex) == 0 {
+			continue
+		}
+		re, err := regexp.Compile(p.MessageRegex)
+		if err != nil {
+			return nil, fmt.Errorf("messageRegex %q: %v", p.MessageRegex, err)
+		}
+		assertions[i].messageRegex = re
+	}
+	return assertions, nil
+}
+
+// matches reports whether ev satisfies the assertion's reason/type/messageRegex filters.
+func (a eventAssertion) matches(ev *v1.Event) bool {
+	if len(a.Reason) > 0 && ev.Reason != a.Reason {
+		return false
+	}
+	if len(a.Type) > 0 && ev.Type != a.Type {
+		return false
+	}
+	if a.messageRegex != nil && !a.messageRegex.MatchString(ev.Message) {
+		return false
+	}
+	return true
+}
+
 // Exec implements Runnable interface
 func (task *CheckPodTask) Exec(ctx context.Context) error {
 	info, err := task.getter.GetObjInfo(task.RefTaskID)
@@ -133,39 +214,115 @@ func (task *CheckPodTask) checkPods(ctx context.Context, info *ObjInfo) error {
 	return nil
 }
 
-// watchPods watches statuses of given pods and compares them with the expected status.
-// The function runs until all statuses are equal to the expected one, or until the timeout, whichever comes first.
+// podProgress tracks, per watched pod, whether its status check has passed and how
+// many times each expectEvents assertion has matched an Event for that pod.
+type podProgress struct {
+	statusOK    bool
+	eventCounts []int
+}
+
+// satisfied reports whether the pod has passed its status check and met every
+// expectEvents threshold.
+func (p *podProgress) satisfied(expectEvents []eventAssertion) bool {
+	if !p.statusOK {
+		return false
+	}
+	for i, a := range expectEvents {
+		if p.eventCounts[i] < a.MinCount {
+			return false
+		}
+	}
+	return true
+}
+
+// podWatchState is shared between the pod and Event informer handlers registered by
+// watchPods. pods maps pod name to its progress and is emptied as pods are satisfied;
+// uids routes an incoming Event (keyed by involvedObject.uid) back to the pod it
+// belongs to, so that events are never matched against pods outside this task. uids is
+// populated both when a pod's own Add/Update callback fires and, as a fallback, the
+// first time one of its Events is matched by involvedObject.name (see verifyEvent).
+type podWatchState struct {
+	mu   sync.Mutex
+	pods map[string]*podProgress
+	uids map[string]string
+}
+
+// watchPods watches statuses (and, if configured, Events) of given pods and compares
+// them with the expected status and event assertions. The function runs until every
+// pod is satisfied, or until the timeout, whichever comes first.
 func (task *CheckPodTask) watchPods(ctx context.Context, info *ObjInfo) error {
-	task.log.Info("Create pod informer", "#pods", len(info.Pods), "timeout", task.Timeout.String())
+	watchEvents := len(task.expectEvents) > 0 || len(task.forbidEvents) > 0
+	task.log.Info("Create pod informer", "#pods", len(info.Pods), "timeout", task.Timeout.String(), "informerMode", task.InformerMode, "watchEvents", watchEvents)
 
 	ctx, cancel := context.WithTimeout(ctx, task.Timeout)
 	defer cancel()
 
-	podMap := utils.NewSyncMap()
+	state := &podWatchState{
+		pods: make(map[string]*podProgress, len(info.Pods)),
+		uids: make(map[string]string, len(info.Pods)),
+	}
 	for _, pod := range info.Pods {
-		podMap.Set(pod, true)
+		state.pods[pod] = &podProgress{eventCounts: make([]int, len(task.expectEvents))}
 	}
 
 	errs := make(chan error)
 
-	factory := informers.NewSharedInformerFactoryWithOptions(task.client, 30*time.Second, informers.WithNamespace(info.Namespace))
-	defer factory.Shutdown()
-
-	informer := factory.Core().V1().Pods().Informer()
+	var podInformer, eventInformer cache.SharedIndexInformer
+	if task.InformerMode == InformerModeDedicated {
+		factory := informers.NewSharedInformerFactoryWithOptions(task.client, 30*time.Second, informers.WithNamespace(info.Namespace))
+		defer factory.Shutdown()
+		podInformer = factory.Core().V1().Pods().Informer()
+		if watchEvents {
+			eventInformer = factory.Core().V1().Events().Informer()
+		}
+	} else {
+		podInformer = task.informers.SharedInformerFactory().Core().V1().Pods().Informer()
+		if watchEvents {
+			eventInformer = task.informers.SharedInformerFactory().Core().V1().Events().Informer()
+		}
+	}
 
-	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+	podHandle, err := podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			task.verifyPod(ctx, podMap, obj, errs)
+			task.verifyPod(ctx, info.Namespace, state, obj, errs)
 		},
 		UpdateFunc: func(_, obj interface{}) {
-			task.verifyPod(ctx, podMap, obj, errs)
+			task.verifyPod(ctx, info.Namespace, state, obj, errs)
 		},
 	})
 	if err != nil {
 		return err
 	}
 
-	go informer.Run(ctx.Done())
+	var eventHandle cache.ResourceEventHandlerRegistration
+	if watchEvents {
+		if eventHandle, err = eventInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				task.verifyEvent(info.Namespace, state, obj, errs)
+			},
+			UpdateFunc: func(_, obj interface{}) {
+				task.verifyEvent(info.Namespace, state, obj, errs)
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	if task.InformerMode == InformerModeDedicated {
+		go podInformer.Run(ctx.Done())
+		if watchEvents {
+			go eventInformer.Run(ctx.Done())
+		}
+	} else {
+		defer func() {
+			_ = podInformer.RemoveEventHandler(podHandle)
+			if watchEvents {
+				_ = eventInformer.RemoveEventHandler(eventHandle)
+			}
+		}()
+		task.informers.SharedInformerFactory().Start(task.informers.InformerStopCh())
+	}
+
 	go func() {
 		list, err := task.client.CoreV1().Pods(info.Namespace).List(ctx, metav1.ListOptions{})
 		if err != nil {
@@ -173,10 +330,10 @@ func (task *CheckPodTask) watchPods(ctx context.Context, info *ObjInfo) error {
 			return
 		}
 		for i := range list.Items {
-			if podMap.Size() == 0 {
+			if state.remaining() == 0 {
 				break
 			}
-			task.verifyPod(ctx, podMap, &list.Items[i], errs)
+			task.verifyPod(ctx, info.Namespace, state, &list.Items[i], errs)
 		}
 	}()
 
@@ -190,6 +347,12 @@ func (task *CheckPodTask) watchPods(ctx context.Context, info *ObjInfo) error {
 	}
 }
 
+func (s *podWatchState) remaining() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pods)
+}
+
 func (task *CheckPodTask) verifyLabels(ctx context.Context, pod *v1.Pod) error {
 	if len(task.NodeLabels) == 0 || pod.Status.Phase != v1.PodRunning {
 		return nil
@@ -209,24 +372,115 @@ func (task *CheckPodTask) verifyLabels(ctx context.Context, pod *v1.Pod) error {
 	return nil
 }
 
-func (task *CheckPodTask) verifyPod(ctx context.Context, podMap *utils.SyncMap, obj interface{}, errs chan error) {
+// verifyPod checks a pod event against the expected status and node labels, and
+// records the pod's UID so subsequent Events can be routed back to it. namespace
+// scopes the match so that, when running against the engine-wide shared informer,
+// pods belonging to other tasks' namespaces are ignored.
+func (task *CheckPodTask) verifyPod(ctx context.Context, namespace string, state *podWatchState, obj interface{}, errs chan error) {
 	pod, ok := obj.(*v1.Pod)
 	if !ok {
 		errs <- fmt.Errorf("%s: unexpected object type %T, expected *v1.Pod", task.ID(), obj)
 		return
 	}
 
-	if _, ok := podMap.Get(pod.Name); ok {
-		status := string(pod.Status.Phase)
-		task.log.V(4).Info("Informer event", "pod", pod.Name, "status", status)
-		if err := task.verifyLabels(ctx, pod); err != nil {
-			errs <- err
-			return
+	if pod.Namespace != namespace {
+		return
+	}
+
+	state.mu.Lock()
+	progress, tracked := state.pods[pod.Name]
+	if tracked {
+		state.uids[string(pod.UID)] = pod.Name
+	}
+	state.mu.Unlock()
+	if !tracked {
+		return
+	}
+
+	status := string(pod.Status.Phase)
+	task.log.V(4).Info("Informer event", "pod", pod.Name, "status", status)
+	if err := task.verifyLabels(ctx, pod); err != nil {
+		errs <- err
+		return
+	}
+
+	state.mu.Lock()
+	progress.statusOK = len(task.Status) == 0 || status == task.Status
+	done := progress.satisfied(task.expectEvents)
+	if done {
+		delete(state.pods, pod.Name)
+	}
+	remaining := len(state.pods)
+	state.mu.Unlock()
+
+	if done && remaining == 0 {
+		task.log.Info("Accounted for all pods")
+		errs <- nil
+	}
+}
+
+// verifyEvent matches an Event against the task's forbidEvents (failing immediately on
+// a match) and expectEvents (incrementing the matching assertion's per-pod counter).
+// Events are scoped to this task's tracked pods via involvedObject.uid, falling back to
+// involvedObject.name when the uid isn't known yet (see the comment below), so they
+// never leak matches across tasks watching different pods in the same namespace.
+func (task *CheckPodTask) verifyEvent(namespace string, state *podWatchState, obj interface{}, errs chan error) {
+	ev, ok := obj.(*v1.Event)
+	if !ok {
+		errs <- fmt.Errorf("%s: unexpected object type %T, expected *v1.Event", task.ID(), obj)
+		return
+	}
+
+	if ev.InvolvedObject.Kind != "Pod" || ev.InvolvedObject.Namespace != namespace {
+		return
+	}
+
+	state.mu.Lock()
+	podName, tracked := state.uids[string(ev.InvolvedObject.UID)]
+	if !tracked {
+		// The pod and Event informers race independently, so this Event can arrive
+		// before the pod's own Add callback has populated state.uids. Fall back to
+		// the pod name carried on the Event itself rather than dropping it.
+		if _, ok := state.pods[ev.InvolvedObject.Name]; ok {
+			podName = ev.InvolvedObject.Name
+			tracked = true
+			state.uids[string(ev.InvolvedObject.UID)] = podName
 		}
-		if sz := podMap.Delete(pod.Name); sz == 0 {
-			task.log.Info("Accounted for all pods")
-			errs <- nil
+	}
+	state.mu.Unlock()
+	if !tracked {
+		return
+	}
+
+	for _, a := range task.forbidEvents {
+		if a.matches(ev) {
+			errs <- fmt.Errorf("%s: pod '%s' received forbidden event reason=%q type=%q message=%q", task.ID(), podName, ev.Reason, ev.Type, ev.Message)
 			return
 		}
 	}
+
+	task.log.V(4).Info("Event", "pod", podName, "reason", ev.Reason, "type", ev.Type, "message", ev.Message)
+
+	state.mu.Lock()
+	progress, tracked := state.pods[podName]
+	if !tracked {
+		state.mu.Unlock()
+		return
+	}
+	for i, a := range task.expectEvents {
+		if a.matches(ev) {
+			progress.eventCounts[i]++
+		}
+	}
+	done := progress.satisfied(task.expectEvents)
+	if done {
+		delete(state.pods, podName)
+	}
+	remaining := len(state.pods)
+	state.mu.Unlock()
+
+	if done && remaining == 0 {
+		task.log.Info("Accounted for all pods")
+		errs <- nil
+	}
 }
@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TestVerifyEventBeforePodTracked reproduces an Event informer delivering an Event for
+// a pod before that pod's own Add/Update callback has run, and confirms verifyEvent
+// still matches it via involvedObject.name instead of silently dropping it.
+func TestVerifyEventBeforePodTracked(t *testing.T) {
+	task := &CheckPodTask{
+		BaseTask: BaseTask{taskID: "check"},
+		forbidEvents: []eventAssertion{
+			{eventAssertionParams: eventAssertionParams{Reason: "BackOff"}},
+		},
+	}
+
+	state := &podWatchState{
+		pods: map[string]*podProgress{"pod-0": {}},
+		uids: map[string]string{},
+	}
+
+	ev := &v1.Event{
+		InvolvedObject: v1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: "ns",
+			Name:      "pod-0",
+			UID:       types.UID("pod-0-uid"),
+		},
+		Reason: "BackOff",
+	}
+
+	errs := make(chan error, 1)
+	task.verifyEvent("ns", state, ev, errs)
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Fatal("expected a forbidden-event error, got nil")
+		}
+	default:
+		t.Fatal("expected verifyEvent to match the event via involvedObject.name and report it")
+	}
+
+	if got, ok := state.uids["pod-0-uid"]; !ok || got != "pod-0" {
+		t.Fatalf("expected verifyEvent to backfill state.uids, got %v", state.uids)
+	}
+}
+
+// TestVerifyEventUntrackedPod confirms events for pods outside this task's tracked set
+// are still ignored rather than matched by name.
+func TestVerifyEventUntrackedPod(t *testing.T) {
+	task := &CheckPodTask{
+		BaseTask: BaseTask{taskID: "check"},
+		forbidEvents: []eventAssertion{
+			{eventAssertionParams: eventAssertionParams{Reason: "BackOff"}},
+		},
+	}
+
+	state := &podWatchState{
+		pods: map[string]*podProgress{"pod-0": {}},
+		uids: map[string]string{},
+	}
+
+	ev := &v1.Event{
+		InvolvedObject: v1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: "ns",
+			Name:      "some-other-pod",
+			UID:       types.UID("some-other-pod-uid"),
+		},
+		Reason: "BackOff",
+	}
+
+	errs := make(chan error, 1)
+	task.verifyEvent("ns", state, ev, errs)
+
+	select {
+	case err := <-errs:
+		t.Fatalf("expected untracked pod's event to be ignored, got error: %v", err)
+	default:
+	}
+
+	if _, ok := state.uids["some-other-pod-uid"]; ok {
+		t.Fatal("expected state.uids to remain unchanged for an untracked pod")
+	}
+}
+
+// TestVerifyPodBackfillsUIDs confirms the normal path -- the pod's own Add/Update
+// callback -- still populates state.uids as before.
+func TestVerifyPodBackfillsUIDs(t *testing.T) {
+	task := &CheckPodTask{
+		BaseTask:           BaseTask{taskID: "check"},
+		checkPodTaskParams: checkPodTaskParams{Status: string(v1.PodRunning)},
+	}
+
+	state := &podWatchState{
+		pods: map[string]*podProgress{"pod-0": {}},
+		uids: map[string]string{},
+	}
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-0", Namespace: "ns", UID: types.UID("pod-0-uid")},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+
+	errs := make(chan error, 1)
+	task.verifyPod(context.Background(), "ns", state, pod, errs)
+
+	if got, ok := state.uids["pod-0-uid"]; !ok || got != "pod-0" {
+		t.Fatalf("expected verifyPod to record state.uids, got %v", state.uids)
+	}
+}
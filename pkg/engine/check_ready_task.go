@@ -0,0 +1,431 @@
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/NVIDIA/knavigator/pkg/config"
+	"github.com/NVIDIA/knavigator/pkg/utils"
+)
+
+// CheckReadyTask represents CheckReady task.
+// A CheckReady task accepts the task ID of a previously executed SubmitObj task and
+// waits until the referenced object is "ready", applying kind-specific rules modeled
+// on Helm 3's kube.IsReady. Unlike CheckObj, which only compares individual fields,
+// CheckReady understands the multi-field readiness semantics of common workload kinds.
+type CheckReadyTask struct {
+	BaseTask
+	checkReadyTaskParams
+
+	client    dynamic.Interface
+	k8sClient *kubernetes.Clientset
+	getter    ObjGetter
+	informers InformerProvider
+}
+
+type checkReadyTaskParams struct {
+	RefTaskID    string        `yaml:"refTaskId"`
+	Timeout      time.Duration `yaml:"timeout"`
+	InformerMode string        `yaml:"informerMode"`
+}
+
+// newCheckReadyTask initializes and returns CheckReadyTask
+func newCheckReadyTask(log logr.Logger, client dynamic.Interface, k8sClient *kubernetes.Clientset, getter ObjGetter, informers InformerProvider, cfg *config.Task) (*CheckReadyTask, error) {
+	if client == nil {
+		return nil, fmt.Errorf("%s/%s: dynamic client is not set", cfg.Type, cfg.ID)
+	}
+
+	task := &CheckReadyTask{
+		BaseTask: BaseTask{
+			log:      log,
+			taskType: cfg.Type,
+			taskID:   cfg.ID,
+		},
+		client:    client,
+		k8sClient: k8sClient,
+		getter:    getter,
+		informers: informers,
+	}
+
+	if err := task.validate(cfg.Params); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// validate initializes and validates parameters for CheckReadyTask
+func (task *CheckReadyTask) validate(params map[string]interface{}) error {
+	data, err := yaml.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("%s: failed to parse parameters: %v", task.ID(), err)
+	}
+	if err = yaml.Unmarshal(data, &task.checkReadyTaskParams); err != nil {
+		return fmt.Errorf("%s: failed to parse parameters: %v", task.ID(), err)
+	}
+
+	if len(task.RefTaskID) == 0 {
+		return fmt.Errorf("%s: missing parameter 'refTaskId'", task.ID())
+	}
+
+	if task.Timeout == 0 {
+		task.Timeout = time.Minute
+	}
+
+	switch task.InformerMode {
+	case "":
+		task.InformerMode = InformerModeShared
+	case InformerModeShared, InformerModeDedicated:
+	default:
+		return fmt.Errorf("%s: invalid 'informerMode' %q, expected %q or %q", task.ID(), task.InformerMode, InformerModeShared, InformerModeDedicated)
+	}
+
+	return nil
+}
+
+// Exec implements Runnable interface
+func (task *CheckReadyTask) Exec(ctx context.Context) error {
+	info, err := task.getter.GetObjInfo(task.RefTaskID)
+	if err != nil {
+		return err
+	}
+
+	if len(info.GVR.Resource) == 0 || len(info.Names) == 0 {
+		return nil
+	}
+
+	return task.watchObjs(ctx, info)
+}
+
+// watchObjs watches the referenced objects and compares them against the kind-specific
+// readiness rules. The function runs until all objects are ready, or until the timeout,
+// whichever comes first.
+func (task *CheckReadyTask) watchObjs(ctx context.Context, info *ObjInfo) error {
+	task.log.Info("Create object informer", "resource", info.GVR.Resource, "#objects", len(info.Names), "timeout", task.Timeout.String(), "informerMode", task.InformerMode)
+
+	ctx, cancel := context.WithTimeout(ctx, task.Timeout)
+	defer cancel()
+
+	pending := utils.NewSyncMap()
+	for _, name := range info.Names {
+		pending.Set(name, true)
+	}
+
+	errs := make(chan error)
+
+	var informer cache.SharedIndexInformer
+	if task.InformerMode == InformerModeDedicated {
+		factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(task.client, 30*time.Second, info.Namespace, nil)
+		defer factory.Shutdown()
+		informer = factory.ForResource(info.GVR).Informer()
+	} else {
+		informer = task.informers.DynamicInformerFactory().ForResource(info.GVR).Informer()
+	}
+
+	handle, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			task.verifyObj(ctx, info.Namespace, pending, obj, errs)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			task.verifyObj(ctx, info.Namespace, pending, obj, errs)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if task.InformerMode == InformerModeDedicated {
+		go informer.Run(ctx.Done())
+	} else {
+		defer func() {
+			_ = informer.RemoveEventHandler(handle)
+		}()
+		task.informers.DynamicInformerFactory().Start(task.informers.InformerStopCh())
+	}
+
+	go func() {
+		list, err := task.client.Resource(info.GVR).Namespace(info.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			errs <- fmt.Errorf("%s: failed to list %s: %v", task.ID(), info.GVR.Resource, err)
+			return
+		}
+		for i := range list.Items {
+			if pending.Size() == 0 {
+				break
+			}
+			task.verifyObj(ctx, info.Namespace, pending, &list.Items[i], errs)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errs:
+			return err
+		}
+	}
+}
+
+// verifyObj checks an object event against the readiness rules. namespace scopes the
+// match so that, when running against the engine-wide shared informer, events
+// belonging to other tasks' namespaces are ignored.
+func (task *CheckReadyTask) verifyObj(ctx context.Context, namespace string, pending *utils.SyncMap, obj interface{}, errs chan error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		errs <- fmt.Errorf("%s: unexpected object type %T, expected *unstructured.Unstructured", task.ID(), obj)
+		return
+	}
+
+	if u.GetNamespace() != namespace {
+		return
+	}
+
+	if _, ok := pending.Get(u.GetName()); !ok {
+		return
+	}
+
+	ready, reason, err := task.isReady(ctx, u)
+	if err != nil {
+		errs <- fmt.Errorf("%s: %v", task.ID(), err)
+		return
+	}
+
+	task.log.V(4).Info("Informer event", "kind", u.GetKind(), "name", u.GetName(), "ready", ready, "reason", reason)
+	if !ready {
+		return
+	}
+
+	if sz := pending.Delete(u.GetName()); sz == 0 {
+		task.log.Info("All objects ready")
+		errs <- nil
+	}
+}
+
+// isReady dispatches on the object's GVK and returns whether it satisfies the
+// readiness rules modeled on Helm 3's kube.IsReady. The reason string explains
+// why an object is still unready; it is logged at V(4) by verifyObj and is not
+// included in the error watchObjs returns on timeout, which is the bare context error.
+func (task *CheckReadyTask) isReady(ctx context.Context, u *unstructured.Unstructured) (bool, string, error) {
+	switch u.GetKind() {
+	case "Deployment":
+		return isDeploymentReady(u)
+	case "StatefulSet":
+		return isStatefulSetReady(u)
+	case "DaemonSet":
+		return isDaemonSetReady(u)
+	case "ReplicaSet":
+		return isReplicaSetReady(u)
+	case "Pod":
+		return isPodReady(u)
+	case "PersistentVolumeClaim":
+		return isPVCReady(u)
+	case "Service":
+		return task.isServiceReady(ctx, u)
+	case "Job":
+		return isJobReady(u)
+	case "CustomResourceDefinition":
+		return isCRDReady(u)
+	default:
+		return false, "", fmt.Errorf("unsupported kind %q for readiness check", u.GetKind())
+	}
+}
+
+func isDeploymentReady(u *unstructured.Unstructured) (bool, string, error) {
+	// spec.replicas reads back as the live, server-persisted value: the API server
+	// already applies the replicas=1 default at admission, so 0 here means the
+	// Deployment was explicitly scaled to zero, not that the field was omitted.
+	replicas, _, _ := unstructured.NestedInt64(u.Object, "spec", "replicas")
+
+	observedGeneration, _, _ := unstructured.NestedInt64(u.Object, "status", "observedGeneration")
+	if observedGeneration < u.GetGeneration() {
+		return false, "observedGeneration behind generation", nil
+	}
+
+	updated, _, _ := unstructured.NestedInt64(u.Object, "status", "updatedReplicas")
+	available, _, _ := unstructured.NestedInt64(u.Object, "status", "availableReplicas")
+	if updated < replicas {
+		return false, "updatedReplicas behind spec.replicas", nil
+	}
+	if available < replicas {
+		return false, "availableReplicas behind spec.replicas", nil
+	}
+
+	return true, "", nil
+}
+
+func isStatefulSetReady(u *unstructured.Unstructured) (bool, string, error) {
+	// See the comment in isDeploymentReady: 0 here is an explicit scale-to-zero, not
+	// an omitted field.
+	replicas, _, _ := unstructured.NestedInt64(u.Object, "spec", "replicas")
+
+	observedGeneration, _, _ := unstructured.NestedInt64(u.Object, "status", "observedGeneration")
+	if observedGeneration < u.GetGeneration() {
+		return false, "observedGeneration behind generation", nil
+	}
+
+	updateRevision, _, _ := unstructured.NestedString(u.Object, "status", "updateRevision")
+	currentRevision, _, _ := unstructured.NestedString(u.Object, "status", "currentRevision")
+	updated, _, _ := unstructured.NestedInt64(u.Object, "status", "updatedReplicas")
+	if updateRevision != "" && updateRevision != currentRevision && updated < replicas {
+		return false, "updatedReplicas behind current update revision", nil
+	}
+
+	readyReplicas, _, _ := unstructured.NestedInt64(u.Object, "status", "readyReplicas")
+	if readyReplicas < replicas {
+		return false, "readyReplicas behind spec.replicas", nil
+	}
+
+	return true, "", nil
+}
+
+func isDaemonSetReady(u *unstructured.Unstructured) (bool, string, error) {
+	// A freshly created DaemonSet reads back with every status counter at zero before
+	// the daemonset controller has reconciled it, which would otherwise satisfy
+	// desired==ready and updated>=desired by coincidence. Require observedGeneration
+	// to have caught up first, as isDeploymentReady/isStatefulSetReady do.
+	observedGeneration, _, _ := unstructured.NestedInt64(u.Object, "status", "observedGeneration")
+	if observedGeneration < u.GetGeneration() {
+		return false, "observedGeneration behind generation", nil
+	}
+
+	desired, _, _ := unstructured.NestedInt64(u.Object, "status", "desiredNumberScheduled")
+	ready, _, _ := unstructured.NestedInt64(u.Object, "status", "numberReady")
+	updated, _, _ := unstructured.NestedInt64(u.Object, "status", "updatedNumberScheduled")
+
+	if desired != ready {
+		return false, "numberReady behind desiredNumberScheduled", nil
+	}
+	if updated < desired {
+		return false, "updatedNumberScheduled behind desiredNumberScheduled", nil
+	}
+
+	return true, "", nil
+}
+
+func isReplicaSetReady(u *unstructured.Unstructured) (bool, string, error) {
+	// See the comment in isDeploymentReady: 0 here is an explicit scale-to-zero, not
+	// an omitted field.
+	replicas, _, _ := unstructured.NestedInt64(u.Object, "spec", "replicas")
+
+	readyReplicas, _, _ := unstructured.NestedInt64(u.Object, "status", "readyReplicas")
+	if readyReplicas < replicas {
+		return false, "readyReplicas behind spec.replicas", nil
+	}
+
+	return true, "", nil
+}
+
+func isPodReady(u *unstructured.Unstructured) (bool, string, error) {
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == "Ready" && condition["status"] == "True" {
+			return true, "", nil
+		}
+	}
+
+	return false, "PodReady condition is not True", nil
+}
+
+func isPVCReady(u *unstructured.Unstructured) (bool, string, error) {
+	phase, _, _ := unstructured.NestedString(u.Object, "status", "phase")
+	if phase != "Bound" {
+		return false, fmt.Sprintf("phase is %q, expected Bound", phase), nil
+	}
+
+	return true, "", nil
+}
+
+func (task *CheckReadyTask) isServiceReady(ctx context.Context, u *unstructured.Unstructured) (bool, string, error) {
+	svcType, _, _ := unstructured.NestedString(u.Object, "spec", "type")
+
+	if svcType == "LoadBalancer" {
+		ingress, _, _ := unstructured.NestedSlice(u.Object, "status", "loadBalancer", "ingress")
+		if len(ingress) == 0 {
+			return false, "loadBalancer ingress not yet populated", nil
+		}
+		return true, "", nil
+	}
+
+	if task.k8sClient == nil {
+		return false, "", fmt.Errorf("Kubernetes client is not set, cannot verify service endpoints")
+	}
+
+	endpoints, err := task.k8sClient.CoreV1().Endpoints(u.GetNamespace()).Get(ctx, u.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get endpoints for service '%s': %v", u.GetName(), err)
+	}
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) > 0 {
+			return true, "", nil
+		}
+	}
+
+	return false, "no endpoints registered", nil
+}
+
+func isJobReady(u *unstructured.Unstructured) (bool, string, error) {
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == "Complete" && condition["status"] == "True" {
+			return true, "", nil
+		}
+	}
+
+	completions, _, _ := unstructured.NestedInt64(u.Object, "spec", "completions")
+	succeeded, _, _ := unstructured.NestedInt64(u.Object, "status", "succeeded")
+	if completions > 0 && succeeded >= completions {
+		return true, "", nil
+	}
+
+	return false, "Complete condition not met", nil
+}
+
+func isCRDReady(u *unstructured.Unstructured) (bool, string, error) {
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == "Established" && condition["status"] == "True" {
+			return true, "", nil
+		}
+	}
+
+	return false, "Established condition is not True", nil
+}
@@ -0,0 +1,445 @@
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func unstructuredFrom(kind string, generation int64, object map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: object}
+	u.SetKind(kind)
+	u.SetGeneration(generation)
+	return u
+}
+
+func TestIsDeploymentReady(t *testing.T) {
+	tests := []struct {
+		name  string
+		obj   *unstructured.Unstructured
+		ready bool
+	}{
+		{
+			name: "ready",
+			obj: unstructuredFrom("Deployment", 1, map[string]interface{}{
+				"spec":   map[string]interface{}{"replicas": int64(3)},
+				"status": map[string]interface{}{"observedGeneration": int64(1), "updatedReplicas": int64(3), "availableReplicas": int64(3)},
+			}),
+			ready: true,
+		},
+		{
+			name: "observedGeneration behind",
+			obj: unstructuredFrom("Deployment", 2, map[string]interface{}{
+				"spec":   map[string]interface{}{"replicas": int64(3)},
+				"status": map[string]interface{}{"observedGeneration": int64(1), "updatedReplicas": int64(3), "availableReplicas": int64(3)},
+			}),
+			ready: false,
+		},
+		{
+			name: "not yet available",
+			obj: unstructuredFrom("Deployment", 1, map[string]interface{}{
+				"spec":   map[string]interface{}{"replicas": int64(3)},
+				"status": map[string]interface{}{"observedGeneration": int64(1), "updatedReplicas": int64(3), "availableReplicas": int64(2)},
+			}),
+			ready: false,
+		},
+		{
+			name: "scaled to zero is ready",
+			obj: unstructuredFrom("Deployment", 1, map[string]interface{}{
+				"spec":   map[string]interface{}{"replicas": int64(0)},
+				"status": map[string]interface{}{"observedGeneration": int64(1), "updatedReplicas": int64(0), "availableReplicas": int64(0)},
+			}),
+			ready: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, reason, err := isDeploymentReady(tt.obj)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ready != tt.ready {
+				t.Fatalf("expected ready=%v, got %v (reason: %q)", tt.ready, ready, reason)
+			}
+		})
+	}
+}
+
+func TestIsStatefulSetReady(t *testing.T) {
+	tests := []struct {
+		name  string
+		obj   *unstructured.Unstructured
+		ready bool
+	}{
+		{
+			name: "ready",
+			obj: unstructuredFrom("StatefulSet", 1, map[string]interface{}{
+				"spec": map[string]interface{}{"replicas": int64(2)},
+				"status": map[string]interface{}{
+					"observedGeneration": int64(1),
+					"updateRevision":     "rev-2",
+					"currentRevision":    "rev-2",
+					"updatedReplicas":    int64(2),
+					"readyReplicas":      int64(2),
+				},
+			}),
+			ready: true,
+		},
+		{
+			name: "update revision rolling out",
+			obj: unstructuredFrom("StatefulSet", 1, map[string]interface{}{
+				"spec": map[string]interface{}{"replicas": int64(2)},
+				"status": map[string]interface{}{
+					"observedGeneration": int64(1),
+					"updateRevision":     "rev-2",
+					"currentRevision":    "rev-1",
+					"updatedReplicas":    int64(1),
+					"readyReplicas":      int64(2),
+				},
+			}),
+			ready: false,
+		},
+		{
+			name: "scaled to zero is ready",
+			obj: unstructuredFrom("StatefulSet", 1, map[string]interface{}{
+				"spec": map[string]interface{}{"replicas": int64(0)},
+				"status": map[string]interface{}{
+					"observedGeneration": int64(1),
+					"readyReplicas":      int64(0),
+				},
+			}),
+			ready: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, reason, err := isStatefulSetReady(tt.obj)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ready != tt.ready {
+				t.Fatalf("expected ready=%v, got %v (reason: %q)", tt.ready, ready, reason)
+			}
+		})
+	}
+}
+
+func TestIsDaemonSetReady(t *testing.T) {
+	tests := []struct {
+		name  string
+		obj   *unstructured.Unstructured
+		ready bool
+	}{
+		{
+			name: "not yet reconciled is not ready",
+			obj: unstructuredFrom("DaemonSet", 1, map[string]interface{}{
+				"status": map[string]interface{}{"observedGeneration": int64(0)},
+			}),
+			ready: false,
+		},
+		{
+			name: "reconciled but not yet scheduled",
+			obj: unstructuredFrom("DaemonSet", 1, map[string]interface{}{
+				"status": map[string]interface{}{
+					"observedGeneration":     int64(1),
+					"desiredNumberScheduled": int64(3),
+					"numberReady":            int64(1),
+					"updatedNumberScheduled": int64(1),
+				},
+			}),
+			ready: false,
+		},
+		{
+			name: "ready",
+			obj: unstructuredFrom("DaemonSet", 1, map[string]interface{}{
+				"status": map[string]interface{}{
+					"observedGeneration":     int64(1),
+					"desiredNumberScheduled": int64(3),
+					"numberReady":            int64(3),
+					"updatedNumberScheduled": int64(3),
+				},
+			}),
+			ready: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, reason, err := isDaemonSetReady(tt.obj)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ready != tt.ready {
+				t.Fatalf("expected ready=%v, got %v (reason: %q)", tt.ready, ready, reason)
+			}
+		})
+	}
+}
+
+func TestIsReplicaSetReady(t *testing.T) {
+	tests := []struct {
+		name  string
+		obj   *unstructured.Unstructured
+		ready bool
+	}{
+		{
+			name: "ready",
+			obj: unstructuredFrom("ReplicaSet", 1, map[string]interface{}{
+				"spec":   map[string]interface{}{"replicas": int64(3)},
+				"status": map[string]interface{}{"readyReplicas": int64(3)},
+			}),
+			ready: true,
+		},
+		{
+			name: "not yet ready",
+			obj: unstructuredFrom("ReplicaSet", 1, map[string]interface{}{
+				"spec":   map[string]interface{}{"replicas": int64(3)},
+				"status": map[string]interface{}{"readyReplicas": int64(1)},
+			}),
+			ready: false,
+		},
+		{
+			name: "scaled to zero is ready",
+			obj: unstructuredFrom("ReplicaSet", 1, map[string]interface{}{
+				"spec":   map[string]interface{}{"replicas": int64(0)},
+				"status": map[string]interface{}{"readyReplicas": int64(0)},
+			}),
+			ready: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, reason, err := isReplicaSetReady(tt.obj)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ready != tt.ready {
+				t.Fatalf("expected ready=%v, got %v (reason: %q)", tt.ready, ready, reason)
+			}
+		})
+	}
+}
+
+func TestIsPodReady(t *testing.T) {
+	tests := []struct {
+		name  string
+		obj   *unstructured.Unstructured
+		ready bool
+	}{
+		{
+			name: "ready",
+			obj: unstructuredFrom("Pod", 1, map[string]interface{}{
+				"status": map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Ready", "status": "True"},
+					},
+				},
+			}),
+			ready: true,
+		},
+		{
+			name: "not ready",
+			obj: unstructuredFrom("Pod", 1, map[string]interface{}{
+				"status": map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Ready", "status": "False"},
+					},
+				},
+			}),
+			ready: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, reason, err := isPodReady(tt.obj)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ready != tt.ready {
+				t.Fatalf("expected ready=%v, got %v (reason: %q)", tt.ready, ready, reason)
+			}
+		})
+	}
+}
+
+func TestIsPVCReady(t *testing.T) {
+	tests := []struct {
+		name  string
+		phase string
+		ready bool
+	}{
+		{name: "bound", phase: "Bound", ready: true},
+		{name: "pending", phase: "Pending", ready: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := unstructuredFrom("PersistentVolumeClaim", 1, map[string]interface{}{
+				"status": map[string]interface{}{"phase": tt.phase},
+			})
+			ready, reason, err := isPVCReady(obj)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ready != tt.ready {
+				t.Fatalf("expected ready=%v, got %v (reason: %q)", tt.ready, ready, reason)
+			}
+		})
+	}
+}
+
+func TestIsJobReady(t *testing.T) {
+	tests := []struct {
+		name  string
+		obj   *unstructured.Unstructured
+		ready bool
+	}{
+		{
+			name: "complete condition",
+			obj: unstructuredFrom("Job", 1, map[string]interface{}{
+				"status": map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Complete", "status": "True"},
+					},
+				},
+			}),
+			ready: true,
+		},
+		{
+			name: "succeeded meets completions without condition",
+			obj: unstructuredFrom("Job", 1, map[string]interface{}{
+				"spec":   map[string]interface{}{"completions": int64(2)},
+				"status": map[string]interface{}{"succeeded": int64(2)},
+			}),
+			ready: true,
+		},
+		{
+			name: "still running",
+			obj: unstructuredFrom("Job", 1, map[string]interface{}{
+				"spec":   map[string]interface{}{"completions": int64(2)},
+				"status": map[string]interface{}{"succeeded": int64(1)},
+			}),
+			ready: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, reason, err := isJobReady(tt.obj)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ready != tt.ready {
+				t.Fatalf("expected ready=%v, got %v (reason: %q)", tt.ready, ready, reason)
+			}
+		})
+	}
+}
+
+func TestIsCRDReady(t *testing.T) {
+	tests := []struct {
+		name  string
+		obj   *unstructured.Unstructured
+		ready bool
+	}{
+		{
+			name: "established",
+			obj: unstructuredFrom("CustomResourceDefinition", 1, map[string]interface{}{
+				"status": map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Established", "status": "True"},
+					},
+				},
+			}),
+			ready: true,
+		},
+		{
+			name: "not established",
+			obj: unstructuredFrom("CustomResourceDefinition", 1, map[string]interface{}{
+				"status": map[string]interface{}{"conditions": []interface{}{}},
+			}),
+			ready: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, reason, err := isCRDReady(tt.obj)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ready != tt.ready {
+				t.Fatalf("expected ready=%v, got %v (reason: %q)", tt.ready, ready, reason)
+			}
+		})
+	}
+}
+
+func TestIsServiceReady(t *testing.T) {
+	task := &CheckReadyTask{}
+
+	t.Run("loadBalancer ingress populated", func(t *testing.T) {
+		obj := unstructuredFrom("Service", 1, map[string]interface{}{
+			"spec": map[string]interface{}{"type": "LoadBalancer"},
+			"status": map[string]interface{}{
+				"loadBalancer": map[string]interface{}{
+					"ingress": []interface{}{map[string]interface{}{"ip": "1.2.3.4"}},
+				},
+			},
+		})
+		ready, _, err := task.isServiceReady(context.Background(), obj)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !ready {
+			t.Fatal("expected service to be ready")
+		}
+	})
+
+	t.Run("loadBalancer ingress pending", func(t *testing.T) {
+		obj := unstructuredFrom("Service", 1, map[string]interface{}{
+			"spec": map[string]interface{}{"type": "LoadBalancer"},
+		})
+		ready, _, err := task.isServiceReady(context.Background(), obj)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ready {
+			t.Fatal("expected service to not be ready")
+		}
+	})
+
+	t.Run("clusterIP without a client cannot be verified", func(t *testing.T) {
+		obj := unstructuredFrom("Service", 1, map[string]interface{}{
+			"spec": map[string]interface{}{"type": "ClusterIP"},
+		})
+		obj.SetName("svc")
+		obj.SetNamespace("ns")
+
+		_, _, err := task.isServiceReady(context.Background(), obj)
+		if err == nil {
+			t.Fatal("expected an error when k8sClient is not set")
+		}
+	})
+}
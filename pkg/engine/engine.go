@@ -24,23 +24,42 @@ import (
 
 	"github.com/go-logr/logr"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
 	"github.com/NVIDIA/knavigator/pkg/config"
 )
 
+// defaultInformerResync is the resync period used by the engine-wide informer
+// factories handed out to tasks running in InformerModeShared.
+const defaultInformerResync = 30 * time.Second
+
 type Engine interface {
 	RunTask(context.Context, *config.Task) error
 	Reset(context.Context) error
 }
 
+// CleanupRegistrar is implemented by Eng and lets a task register a function to run
+// when the engine is Reset. It is used by tasks that keep running in the background
+// after Exec returns (e.g. TaskNodeHeartbeat) and need a hook to stop cleanly.
+type CleanupRegistrar interface {
+	RegisterCleanup(func())
+}
+
 type Eng struct {
 	log           logr.Logger
 	mutex         sync.Mutex
 	k8sClient     *kubernetes.Clientset
 	dynamicClient *dynamic.DynamicClient
 	objMap        map[string]*ObjInfo
+
+	informerFactory        informers.SharedInformerFactory
+	dynamicInformerFactory dynamicinformer.DynamicSharedInformerFactory
+	informerStopCh         chan struct{}
+
+	cleanupFuncs []func()
 }
 
 func New(log logr.Logger, config *rest.Config, sim ...bool) (*Eng, error) {
@@ -65,13 +84,10 @@ func New(log logr.Logger, config *rest.Config, sim ...bool) (*Eng, error) {
 	return eng, nil
 }
 
+// Run executes every task in testconfig as a DAG over each task's DependsOn list (see
+// scheduler.go), then resets the engine regardless of the outcome.
 func Run(ctx context.Context, eng Engine, testconfig *config.TaskConfig) error {
-	var errExec error
-	for _, cfg := range testconfig.Tasks {
-		if errExec = eng.RunTask(ctx, cfg); errExec != nil {
-			break
-		}
-	}
+	errExec := runDAG(ctx, eng, testconfig)
 
 	errReset := eng.Reset(ctx)
 
@@ -133,8 +149,19 @@ func (eng *Eng) GetTask(cfg *config.Task) (Runnable, error) {
 		return task, nil
 	case TaskUpdateNodes:
 		return newUpdateNodesTask(eng.log, eng.k8sClient, cfg)
+	case TaskNodeHeartbeat:
+		return newNodeHeartbeatTask(eng.log, eng.k8sClient, eng, cfg)
 	case TaskCheckPod:
-		task, err := newCheckPodTask(eng.log, eng.k8sClient, eng, cfg)
+		task, err := newCheckPodTask(eng.log, eng.k8sClient, eng, eng, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := eng.objMap[task.RefTaskID]; !ok {
+			return nil, fmt.Errorf("%s: unreferenced task ID %s", task.ID(), task.RefTaskID)
+		}
+		return task, nil
+	case TaskCheckReady:
+		task, err := newCheckReadyTask(eng.log, eng.dynamicClient, eng.k8sClient, eng, eng, cfg)
 		if err != nil {
 			return nil, err
 		}
@@ -188,6 +215,61 @@ func (eng *Eng) GetObjInfo(taskID string) (*ObjInfo, error) {
 	return info, nil
 }
 
+// SharedInformerFactory implements InformerProvider and returns the engine-wide
+// SharedInformerFactory, starting its stop channel on first use.
+func (eng *Eng) SharedInformerFactory() informers.SharedInformerFactory {
+	eng.mutex.Lock()
+	defer eng.mutex.Unlock()
+
+	if eng.informerFactory == nil {
+		eng.ensureInformerStopChLocked()
+		eng.informerFactory = informers.NewSharedInformerFactory(eng.k8sClient, defaultInformerResync)
+	}
+
+	return eng.informerFactory
+}
+
+// DynamicInformerFactory implements InformerProvider and returns the engine-wide
+// DynamicSharedInformerFactory, starting its stop channel on first use.
+func (eng *Eng) DynamicInformerFactory() dynamicinformer.DynamicSharedInformerFactory {
+	eng.mutex.Lock()
+	defer eng.mutex.Unlock()
+
+	if eng.dynamicInformerFactory == nil {
+		eng.ensureInformerStopChLocked()
+		eng.dynamicInformerFactory = dynamicinformer.NewDynamicSharedInformerFactory(eng.dynamicClient, defaultInformerResync)
+	}
+
+	return eng.dynamicInformerFactory
+}
+
+// InformerStopCh implements InformerProvider and returns the channel that stops every
+// factory handed out by this engine; it is closed on Reset.
+func (eng *Eng) InformerStopCh() <-chan struct{} {
+	eng.mutex.Lock()
+	defer eng.mutex.Unlock()
+
+	eng.ensureInformerStopChLocked()
+
+	return eng.informerStopCh
+}
+
+// ensureInformerStopChLocked lazily creates the stop channel shared by every engine-wide
+// informer factory. Callers must hold eng.mutex.
+func (eng *Eng) ensureInformerStopChLocked() {
+	if eng.informerStopCh == nil {
+		eng.informerStopCh = make(chan struct{})
+	}
+}
+
+// RegisterCleanup implements CleanupRegistrar and records fn to run on Reset.
+func (eng *Eng) RegisterCleanup(fn func()) {
+	eng.mutex.Lock()
+	defer eng.mutex.Unlock()
+
+	eng.cleanupFuncs = append(eng.cleanupFuncs, fn)
+}
+
 func execRunnable(ctx context.Context, log logr.Logger, r Runnable) error {
 	id := r.ID()
 	log.Info("Starting task", "id", id)
@@ -200,6 +282,31 @@ func execRunnable(ctx context.Context, log logr.Logger, r Runnable) error {
 	return nil
 }
 
+// Reset tears down every informer factory started during the test run, then clears
+// the recorded object info so the engine can be reused by a subsequent run.
 func (eng *Eng) Reset(ctx context.Context) error {
+	eng.mutex.Lock()
+	defer eng.mutex.Unlock()
+
+	for _, fn := range eng.cleanupFuncs {
+		fn()
+	}
+	eng.cleanupFuncs = nil
+
+	if eng.informerStopCh != nil {
+		close(eng.informerStopCh)
+		eng.informerStopCh = nil
+	}
+	if eng.informerFactory != nil {
+		eng.informerFactory.Shutdown()
+		eng.informerFactory = nil
+	}
+	if eng.dynamicInformerFactory != nil {
+		eng.dynamicInformerFactory.Shutdown()
+		eng.dynamicInformerFactory = nil
+	}
+
+	eng.objMap = make(map[string]*ObjInfo)
+
 	return nil
 }
@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package engine
+
+import (
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+)
+
+// Informer modes control whether a watching task (e.g. CheckPod, CheckReady) consumes
+// events from the engine-wide shared informer factories or spins up a factory of its own.
+const (
+	// InformerModeShared routes a task's event handlers through the engine-wide
+	// SharedInformerFactory/DynamicSharedInformerFactory. This is the default: scenarios
+	// that submit hundreds of objects and run several watching tasks back-to-back share
+	// a single watch per resource type instead of multiplying apiserver load.
+	InformerModeShared = "Shared"
+
+	// InformerModeDedicated spins up a factory scoped to a single task, isolating its
+	// event stream from every other task. Use it for replay scenarios or very high-churn
+	// checks where sharing the common watch could drop or delay events for other tasks.
+	InformerModeDedicated = "Dedicated"
+)
+
+// InformerProvider is implemented by Eng and gives tasks access to the engine-wide
+// informer factories without exposing the rest of the engine's internals.
+type InformerProvider interface {
+	// SharedInformerFactory returns the engine-wide SharedInformerFactory, creating it
+	// on first use. It does not start the factory; callers call Start themselves.
+	SharedInformerFactory() informers.SharedInformerFactory
+
+	// DynamicInformerFactory returns the engine-wide DynamicSharedInformerFactory,
+	// creating it on first use. It does not start the factory; callers call Start themselves.
+	DynamicInformerFactory() dynamicinformer.DynamicSharedInformerFactory
+
+	// InformerStopCh returns the channel that stops every factory handed out by this
+	// provider; it is closed on Reset.
+	InformerStopCh() <-chan struct{}
+}
@@ -0,0 +1,255 @@
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	"gopkg.in/yaml.v3"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/NVIDIA/knavigator/pkg/config"
+)
+
+// nodeLeaseNamespace is where kubelet (and, here, NodeHeartbeatTask) publish node Leases.
+const nodeLeaseNamespace = "kube-node-lease"
+
+// nodeHeartbeatShardSize bounds how many nodes a single renewal goroutine owns, so that
+// a scenario simulating thousands of nodes spreads lease renewal across many goroutines
+// instead of serializing every Update behind one ticker.
+const nodeHeartbeatShardSize = 100
+
+// NodeHeartbeatTask represents NodeHeartbeat task.
+// A NodeHeartbeat task continuously renews the coordination.k8s.io/v1 Lease of a set of
+// nodes selected by label and/or explicit name, mimicking KWOK's NodeLeaseController.
+// TaskUpdateNodes can create and mark nodes Ready, but without a real node-lifecycle
+// controller those nodes go NotReady once their lease expires; NodeHeartbeat keeps them
+// Ready for the life of a scenario so simulated-node scheduler tests don't require
+// standing up KWOK itself.
+type NodeHeartbeatTask struct {
+	BaseTask
+	nodeHeartbeatTaskParams
+
+	client    kubernetes.Interface
+	registrar CleanupRegistrar
+}
+
+type nodeHeartbeatTaskParams struct {
+	NodeSelector            string        `yaml:"nodeSelector"`
+	NodeNames               []string      `yaml:"nodeNames"`
+	LeaseDurationSeconds    int32         `yaml:"leaseDurationSeconds"`
+	RenewInterval           time.Duration `yaml:"renewInterval"`
+	Duration                time.Duration `yaml:"duration"`
+	OwnerReferencesFromNode bool          `yaml:"ownerReferencesFromNode"`
+}
+
+// newNodeHeartbeatTask initializes and returns NodeHeartbeatTask
+func newNodeHeartbeatTask(log logr.Logger, client kubernetes.Interface, registrar CleanupRegistrar, cfg *config.Task) (*NodeHeartbeatTask, error) {
+	if client == nil {
+		return nil, fmt.Errorf("%s/%s: Kubernetes client is not set", cfg.Type, cfg.ID)
+	}
+
+	task := &NodeHeartbeatTask{
+		BaseTask: BaseTask{
+			log:      log,
+			taskType: cfg.Type,
+			taskID:   cfg.ID,
+		},
+		client:    client,
+		registrar: registrar,
+	}
+
+	if err := task.validate(cfg.Params); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// validate initializes and validates parameters for NodeHeartbeatTask
+func (task *NodeHeartbeatTask) validate(params map[string]interface{}) error {
+	data, err := yaml.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("%s: failed to parse parameters: %v", task.ID(), err)
+	}
+	if err = yaml.Unmarshal(data, &task.nodeHeartbeatTaskParams); err != nil {
+		return fmt.Errorf("%s: failed to parse parameters: %v", task.ID(), err)
+	}
+
+	if len(task.NodeSelector) == 0 && len(task.NodeNames) == 0 {
+		return fmt.Errorf("%s: missing parameters 'nodeSelector' and/or 'nodeNames'", task.ID())
+	}
+
+	if task.LeaseDurationSeconds == 0 {
+		task.LeaseDurationSeconds = 40
+	}
+	if task.RenewInterval == 0 {
+		task.RenewInterval = 10 * time.Second
+	}
+	if task.Duration == 0 {
+		task.Duration = -1 // lifetime of the engine
+	}
+
+	return nil
+}
+
+// Exec implements Runnable interface. It starts the renewal goroutines and returns
+// immediately; the goroutines keep running, cooperating with TaskUpdateNodes to keep
+// the selected nodes Ready, until task.Duration elapses or the engine is Reset.
+func (task *NodeHeartbeatTask) Exec(ctx context.Context) error {
+	nodes, err := task.listNodes(ctx)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		return fmt.Errorf("%s: no nodes matched nodeSelector %q / nodeNames %v", task.ID(), task.NodeSelector, task.NodeNames)
+	}
+
+	hbCtx, cancel := context.WithCancel(context.Background())
+	if task.Duration > 0 {
+		hbCtx, cancel = context.WithTimeout(hbCtx, task.Duration)
+	}
+	task.registrar.RegisterCleanup(cancel)
+
+	task.log.Info("Starting node lease heartbeat", "#nodes", len(nodes), "renewInterval", task.RenewInterval.String(), "duration", task.Duration.String())
+
+	for i := 0; i < len(nodes); i += nodeHeartbeatShardSize {
+		end := i + nodeHeartbeatShardSize
+		if end > len(nodes) {
+			end = len(nodes)
+		}
+		go task.renewLeases(hbCtx, nodes[i:end])
+	}
+
+	return nil
+}
+
+// listNodes resolves the task's nodeSelector and nodeNames into the set of nodes to
+// heartbeat, de-duplicating nodes matched by both, and returns them sorted by name so
+// shard assignment is deterministic across runs.
+func (task *NodeHeartbeatTask) listNodes(ctx context.Context) ([]corev1.Node, error) {
+	byName := make(map[string]corev1.Node)
+
+	if len(task.NodeSelector) > 0 {
+		list, err := task.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: task.NodeSelector})
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to list nodes: %v", task.ID(), err)
+		}
+		for _, node := range list.Items {
+			byName[node.Name] = node
+		}
+	}
+
+	for _, name := range task.NodeNames {
+		if _, ok := byName[name]; ok {
+			continue
+		}
+		node, err := task.client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to get node '%s': %v", task.ID(), name, err)
+		}
+		byName[node.Name] = *node
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	nodes := make([]corev1.Node, len(names))
+	for i, name := range names {
+		nodes[i] = byName[name]
+	}
+
+	return nodes, nil
+}
+
+// renewLeases owns a shard of nodes and renews their Leases on every tick until ctx
+// is cancelled, i.e. until task.Duration elapses or the engine is Reset.
+func (task *NodeHeartbeatTask) renewLeases(ctx context.Context, nodes []corev1.Node) {
+	for i := range nodes {
+		task.renewLease(ctx, &nodes[i])
+	}
+
+	ticker := time.NewTicker(task.RenewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for i := range nodes {
+				task.renewLease(ctx, &nodes[i])
+			}
+		}
+	}
+}
+
+// renewLease upserts the given node's Lease in kube-node-lease, setting renewTime to
+// now, mimicking what kubelet's NodeLeaseController does for a real node.
+func (task *NodeHeartbeatTask) renewLease(ctx context.Context, node *corev1.Node) {
+	now := metav1.NewMicroTime(time.Now())
+	leaseDurationSeconds := task.LeaseDurationSeconds
+	holderIdentity := node.Name
+
+	lease, err := task.client.CoordinationV1().Leases(nodeLeaseNamespace).Get(ctx, node.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      node.Name,
+				Namespace: nodeLeaseNamespace,
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &holderIdentity,
+				LeaseDurationSeconds: &leaseDurationSeconds,
+				RenewTime:            &now,
+			},
+		}
+		if task.OwnerReferencesFromNode {
+			lease.OwnerReferences = node.OwnerReferences
+		}
+		if _, err := task.client.CoordinationV1().Leases(nodeLeaseNamespace).Create(ctx, lease, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+			task.log.Error(err, "Failed to create node lease", "node", node.Name)
+		}
+		return
+	}
+	if err != nil {
+		task.log.Error(err, "Failed to get node lease", "node", node.Name)
+		return
+	}
+
+	lease = lease.DeepCopy()
+	lease.Spec.RenewTime = &now
+	lease.Spec.LeaseDurationSeconds = &leaseDurationSeconds
+	if task.OwnerReferencesFromNode {
+		lease.OwnerReferences = node.OwnerReferences
+	}
+
+	if _, err := task.client.CoordinationV1().Leases(nodeLeaseNamespace).Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		task.log.Error(err, "Failed to renew node lease", "node", node.Name)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestNodeHeartbeatTask(client *fake.Clientset) *NodeHeartbeatTask {
+	return &NodeHeartbeatTask{
+		BaseTask: BaseTask{taskID: "heartbeat"},
+		nodeHeartbeatTaskParams: nodeHeartbeatTaskParams{
+			LeaseDurationSeconds: 40,
+		},
+		client: client,
+	}
+}
+
+func TestRenewLeaseCreatesMissingLease(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	task := newTestNodeHeartbeatTask(client)
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-0"}}
+
+	task.renewLease(context.Background(), node)
+
+	lease, err := client.CoordinationV1().Leases(nodeLeaseNamespace).Get(context.Background(), "node-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected lease to be created, got error: %v", err)
+	}
+	if lease.Spec.RenewTime == nil {
+		t.Fatal("expected renewTime to be set on the created lease")
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != "node-0" {
+		t.Fatalf("expected holderIdentity 'node-0', got %v", lease.Spec.HolderIdentity)
+	}
+}
+
+func TestRenewLeaseUpdatesExistingLease(t *testing.T) {
+	holderIdentity := "node-0"
+	leaseDurationSeconds := int32(40)
+	staleRenewTime := metav1.NewMicroTime(metav1.Now().Add(-time.Hour))
+
+	client := fake.NewSimpleClientset(&coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-0", Namespace: nodeLeaseNamespace},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &holderIdentity,
+			LeaseDurationSeconds: &leaseDurationSeconds,
+			RenewTime:            &staleRenewTime,
+		},
+	})
+	task := newTestNodeHeartbeatTask(client)
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-0"}}
+
+	task.renewLease(context.Background(), node)
+
+	lease, err := client.CoordinationV1().Leases(nodeLeaseNamespace).Get(context.Background(), "node-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !lease.Spec.RenewTime.After(staleRenewTime.Time) {
+		t.Fatalf("expected renewTime to advance past %v, got %v", staleRenewTime, lease.Spec.RenewTime)
+	}
+}
+
+func TestRenewLeaseCopiesNodeOwnerReferences(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	task := newTestNodeHeartbeatTask(client)
+	task.OwnerReferencesFromNode = true
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-0",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "v1", Kind: "Pod", Name: "owner", UID: "owner-uid"},
+			},
+		},
+	}
+
+	task.renewLease(context.Background(), node)
+
+	lease, err := client.CoordinationV1().Leases(nodeLeaseNamespace).Get(context.Background(), "node-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lease.OwnerReferences) != 1 || lease.OwnerReferences[0].Name != "owner" {
+		t.Fatalf("expected lease to carry node's owner references, got %v", lease.OwnerReferences)
+	}
+}
+
+func TestRenewLeasesRenewsEveryNodeInShard(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	task := newTestNodeHeartbeatTask(client)
+
+	nodes := []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-0"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	task.renewLeases(ctx, nodes)
+
+	for _, name := range []string{"node-0", "node-1"} {
+		if _, err := client.CoordinationV1().Leases(nodeLeaseNamespace).Get(context.Background(), name, metav1.GetOptions{}); err != nil {
+			t.Fatalf("expected lease for %s to be created, got error: %v", name, err)
+		}
+	}
+}
+
+func TestListNodesUnionsSelectorAndNames(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "selected", Labels: map[string]string{"role": "worker"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "named"}},
+	)
+	task := newTestNodeHeartbeatTask(client)
+	task.NodeSelector = "role=worker"
+	task.NodeNames = []string{"named", "selected"}
+
+	nodes, err := task.listNodes(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	names := make([]string, len(nodes))
+	for i, n := range nodes {
+		names[i] = n.Name
+	}
+	if want := []string{"named", "selected"}; !equalStrings(names, want) {
+		t.Fatalf("expected %v, got %v", want, names)
+	}
+}
+
+func TestListNodesMissingNameErrors(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	task := newTestNodeHeartbeatTask(client)
+	task.NodeNames = []string{"does-not-exist"}
+
+	if _, err := task.listNodes(context.Background()); err == nil {
+		t.Fatal("expected an error for a nodeNames entry that doesn't exist")
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,242 @@
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/NVIDIA/knavigator/pkg/config"
+)
+
+// defaultMaxParallelism is used when a TaskConfig doesn't set MaxParallelism: tasks
+// still run as a DAG, but only one at a time, matching the previous strictly
+// sequential behavior.
+const defaultMaxParallelism = 1
+
+// runDAG schedules testconfig.Tasks as a DAG over each task's effective dependencies
+// (see effectiveDependsOn) and dispatches them with a single mutex-guarded dispatcher,
+// so that among tasks that become ready together, the one declared earliest in
+// testconfig.Tasks always starts first — matching the order a reader gets from
+// scanning the YAML, and the order the previous strictly sequential engine ran them in.
+// Tasks with no unsatisfied dependency run concurrently up to testconfig.MaxParallelism.
+// This lets a scenario fan out many independent tasks (e.g. submitting hundreds of
+// jobs) and fan back in on a single dependent task (e.g. a CheckPod covering all of
+// them) without serializing work that doesn't depend on itself.
+//
+// On the first task error, the context passed to every in-flight eng.RunTask call is
+// cancelled; tasks that haven't started are skipped (not run) so the scheduler can
+// still unwind, and runDAG returns the first error once every task has either run or
+// been skipped.
+func runDAG(ctx context.Context, eng Engine, testconfig *config.TaskConfig) error {
+	tasks := testconfig.Tasks
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*config.Task, len(tasks))
+	order := make(map[string]int, len(tasks))
+	for i, cfg := range tasks {
+		if _, ok := byID[cfg.ID]; ok {
+			return fmt.Errorf("duplicate task ID %q", cfg.ID)
+		}
+		byID[cfg.ID] = cfg
+		order[cfg.ID] = i
+	}
+
+	dependsOn := make(map[string][]string, len(tasks))
+	for _, cfg := range tasks {
+		dependsOn[cfg.ID] = effectiveDependsOn(cfg, byID)
+	}
+
+	if err := detectCycles(tasks, byID, dependsOn); err != nil {
+		return err
+	}
+
+	maxParallelism := testconfig.MaxParallelism
+	if maxParallelism <= 0 {
+		maxParallelism = defaultMaxParallelism
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	remaining := make(map[string]int, len(tasks))
+	dependents := make(map[string][]string, len(tasks))
+	for _, cfg := range tasks {
+		remaining[cfg.ID] = len(dependsOn[cfg.ID])
+		for _, depID := range dependsOn[cfg.ID] {
+			dependents[depID] = append(dependents[depID], cfg.ID)
+		}
+	}
+
+	var ready []string
+	for _, cfg := range tasks {
+		if remaining[cfg.ID] == 0 {
+			ready = append(ready, cfg.ID)
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		errExec  error
+		inFlight int
+	)
+	completions := make(chan string, len(tasks))
+
+	// dispatch starts every ready task it can, in declaration order, up to
+	// maxParallelism concurrently in-flight. It's re-invoked after every completion,
+	// under the same mutex that guards `ready`/`inFlight`, so dispatch decisions are
+	// always made from a consistent view of the graph.
+	var dispatch func()
+	dispatch = func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for len(ready) > 0 && inFlight < maxParallelism {
+			id := ready[0]
+			ready = ready[1:]
+			inFlight++
+
+			cfg := byID[id]
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				if ctx.Err() == nil {
+					if err := eng.RunTask(ctx, cfg); err != nil {
+						mu.Lock()
+						if errExec == nil {
+							errExec = err
+						}
+						mu.Unlock()
+						cancel()
+					}
+				}
+
+				completions <- cfg.ID
+			}()
+		}
+	}
+
+	dispatch()
+
+	for range tasks {
+		id := <-completions
+
+		mu.Lock()
+		inFlight--
+		for _, depID := range dependents[id] {
+			remaining[depID]--
+			if remaining[depID] == 0 {
+				ready = insertReady(ready, depID, order)
+			}
+		}
+		mu.Unlock()
+
+		dispatch()
+	}
+
+	wg.Wait()
+
+	return errExec
+}
+
+// insertReady inserts id into ready, which is kept sorted by declaration order, so
+// that tasks unblocked by different completions still dispatch in the order they
+// were declared rather than the order their last dependency happened to finish.
+func insertReady(ready []string, id string, order map[string]int) []string {
+	i := sort.Search(len(ready), func(i int) bool { return order[ready[i]] > order[id] })
+	ready = append(ready, "")
+	copy(ready[i+1:], ready[i:])
+	ready[i] = id
+	return ready
+}
+
+// effectiveDependsOn returns cfg's explicit DependsOn list, unioned with the implicit
+// dependency derived from cfg's own `refTaskId` parameter (the same parameter GetTask
+// already requires to reference a prior task's recorded ObjInfo). The union matters:
+// a task can set dependsOn for an unrelated ordering reason while still referencing a
+// different task via refTaskId, and dropping the implicit edge in that case would
+// reopen the producer-before-consumer race under maxParallelism > 1 that refTaskId
+// inference exists to close.
+func effectiveDependsOn(cfg *config.Task, byID map[string]*config.Task) []string {
+	dependsOn := cfg.DependsOn
+
+	refTaskID, ok := cfg.Params["refTaskId"].(string)
+	if !ok || len(refTaskID) == 0 {
+		return dependsOn
+	}
+	if _, ok := byID[refTaskID]; !ok {
+		return dependsOn
+	}
+
+	for _, id := range dependsOn {
+		if id == refTaskID {
+			return dependsOn
+		}
+	}
+
+	return append(append([]string{}, dependsOn...), refTaskID)
+}
+
+// detectCycles walks each task's effective dependency edges and fails the run before
+// any task executes if the tasks don't form a DAG or a task's explicit dependsOn
+// references an unknown task ID.
+func detectCycles(tasks []*config.Task, byID map[string]*config.Task, dependsOn map[string][]string) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(tasks))
+
+	var visit func(id string, stack []string) error
+	visit = func(id string, stack []string) error {
+		switch state[id] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("cyclic task dependency: %s", strings.Join(append(stack, id), " -> "))
+		}
+
+		state[id] = visiting
+		for _, depID := range dependsOn[id] {
+			if _, ok := byID[depID]; !ok {
+				return fmt.Errorf("%s: dependsOn references unknown task ID %q", id, depID)
+			}
+			if err := visit(depID, append(stack, id)); err != nil {
+				return err
+			}
+		}
+		state[id] = visited
+
+		return nil
+	}
+
+	for _, cfg := range tasks {
+		if err := visit(cfg.ID, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
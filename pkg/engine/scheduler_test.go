@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/NVIDIA/knavigator/pkg/config"
+)
+
+// fakeEngine records the order in which RunTask is invoked and optionally fails
+// individual task IDs, so tests can assert on scheduling behavior without a real
+// Kubernetes client.
+type fakeEngine struct {
+	mu     sync.Mutex
+	order  []string
+	runErr map[string]error
+}
+
+func (f *fakeEngine) RunTask(_ context.Context, cfg *config.Task) error {
+	f.mu.Lock()
+	f.order = append(f.order, cfg.ID)
+	f.mu.Unlock()
+	return f.runErr[cfg.ID]
+}
+
+func (f *fakeEngine) Reset(_ context.Context) error {
+	return nil
+}
+
+func TestRunDAGDependsOnOrder(t *testing.T) {
+	tasks := []*config.Task{
+		{ID: "b", DependsOn: []string{"a"}},
+		{ID: "a"},
+	}
+
+	eng := &fakeEngine{runErr: map[string]error{}}
+	if err := runDAG(context.Background(), eng, &config.TaskConfig{Tasks: tasks}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{"a", "b"}; !reflect.DeepEqual(eng.order, want) {
+		t.Fatalf("expected %v, got %v", want, eng.order)
+	}
+}
+
+func TestRunDAGImplicitRefTaskIDOrder(t *testing.T) {
+	tasks := []*config.Task{
+		{ID: "check", Params: map[string]interface{}{"refTaskId": "submit"}},
+		{ID: "submit"},
+	}
+
+	eng := &fakeEngine{runErr: map[string]error{}}
+	if err := runDAG(context.Background(), eng, &config.TaskConfig{Tasks: tasks}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{"submit", "check"}; !reflect.DeepEqual(eng.order, want) {
+		t.Fatalf("expected %v, got %v", want, eng.order)
+	}
+}
+
+func TestRunDAGUnionsExplicitDependsOnWithImplicitRefTaskID(t *testing.T) {
+	// "check" depends on "gate" for an unrelated ordering reason, but also references
+	// "submit" via refTaskId. Both edges must hold: "check" must not run until both
+	// "gate" and "submit" have completed.
+	tasks := []*config.Task{
+		{ID: "check", DependsOn: []string{"gate"}, Params: map[string]interface{}{"refTaskId": "submit"}},
+		{ID: "submit"},
+		{ID: "gate"},
+	}
+
+	eng := &fakeEngine{runErr: map[string]error{}}
+	if err := runDAG(context.Background(), eng, &config.TaskConfig{Tasks: tasks, MaxParallelism: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pos := make(map[string]int, len(eng.order))
+	for i, id := range eng.order {
+		pos[id] = i
+	}
+	if pos["check"] < pos["submit"] {
+		t.Fatalf("expected 'check' to run after 'submit', got order %v", eng.order)
+	}
+	if pos["check"] < pos["gate"] {
+		t.Fatalf("expected 'check' to run after 'gate', got order %v", eng.order)
+	}
+}
+
+func TestRunDAGDeclarationOrderAmongReady(t *testing.T) {
+	tasks := []*config.Task{
+		{ID: "t1"},
+		{ID: "t2"},
+		{ID: "t3"},
+	}
+
+	eng := &fakeEngine{runErr: map[string]error{}}
+	if err := runDAG(context.Background(), eng, &config.TaskConfig{Tasks: tasks, MaxParallelism: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{"t1", "t2", "t3"}; !reflect.DeepEqual(eng.order, want) {
+		t.Fatalf("expected %v, got %v", want, eng.order)
+	}
+}
+
+func TestRunDAGCycleDetected(t *testing.T) {
+	tasks := []*config.Task{
+		{ID: "a", DependsOn: []string{"b"}},
+		{ID: "b", DependsOn: []string{"a"}},
+	}
+
+	eng := &fakeEngine{runErr: map[string]error{}}
+	if err := runDAG(context.Background(), eng, &config.TaskConfig{Tasks: tasks}); err == nil {
+		t.Fatal("expected a cyclic dependency error")
+	}
+
+	if len(eng.order) != 0 {
+		t.Fatalf("expected no tasks to run, got %v", eng.order)
+	}
+}
+
+func TestRunDAGCancelsOnError(t *testing.T) {
+	tasks := []*config.Task{
+		{ID: "a"},
+		{ID: "b", DependsOn: []string{"a"}},
+	}
+
+	eng := &fakeEngine{runErr: map[string]error{"a": errors.New("boom")}}
+	err := runDAG(context.Background(), eng, &config.TaskConfig{Tasks: tasks})
+	if err == nil || err.Error() != "boom" {
+		t.Fatalf("expected 'boom' error, got %v", err)
+	}
+
+	for _, id := range eng.order {
+		if id == "b" {
+			t.Fatal("task 'b' should not have run after its dependency 'a' failed")
+		}
+	}
+}